@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"text/tabwriter"
+	"time"
 
+	"github.com/lambdalabs/fmctl/pkg/dra"
+	"github.com/lambdalabs/fmctl/pkg/fmplan"
 	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+	"github.com/lambdalabs/fmctl/pkg/fmserve"
+	"github.com/lambdalabs/fmctl/pkg/fmwatch"
+	"github.com/lambdalabs/fmctl/pkg/nvmlx"
 )
 
 var (
@@ -17,6 +26,26 @@ var (
 	timeout    = flag.Uint("timeout", 5000, "Connection timeout in milliseconds")
 	jsonOutput = flag.Bool("json", false, "Output in JSON format")
 	verbose    = flag.Bool("verbose", false, "Verbose output")
+
+	draNodeName         = flag.String("dra-node-name", "", "Node name to register the DRA plugin under (dra command)")
+	draKubeletPluginDir = flag.String("dra-kubelet-plugin-dir", "/var/lib/kubelet/plugins/"+dra.DriverName, "Kubelet plugin registration directory (dra command)")
+	draCDIRoot          = flag.String("dra-cdi-root", "/var/run/cdi", "Directory to write CDI specs into (dra command)")
+	draPollInterval     = flag.Duration("dra-poll-interval", 30*time.Second, "How often to refresh partition state from FM and republish the ResourceSlice (dra command)")
+
+	serveGRPCAddr     = flag.String("serve-grpc-addr", ":9414", "gRPC listen address (serve command)")
+	serveHTTPAddr     = flag.String("serve-http-addr", ":9415", "REST gateway listen address (serve command)")
+	serveMetricsAddr  = flag.String("serve-metrics-addr", ":9416", "Prometheus /metrics listen address (serve command)")
+	servePollInterval = flag.Duration("serve-poll-interval", 10*time.Second, "How often to refresh partition state from FM (serve command)")
+
+	applyFile         = flag.String("f", "partitions.hcl", "Path to the declarative partitions spec (apply/plan commands)")
+	applyWatch        = flag.Bool("watch", false, "Re-reconcile on spec file change or poll interval (apply command)")
+	applyPollInterval = flag.Duration("apply-poll-interval", 30*time.Second, "Poll interval used by --watch (apply command)")
+
+	enrich = flag.Bool("enrich", false, "Resolve empty GPU UUID/PCI bus ID via NVML, by physical ID (list/status commands)")
+
+	watchInterval = flag.Duration("watch-interval", 10*time.Second, "Poll interval between snapshots (watch command)")
+	watchWebhook  = flag.String("watch-webhook", "", "If set, POST each event as JSON to this URL (watch command)")
+	watchSyslog   = flag.Bool("watch-syslog", false, "If set, also write each event to syslog (watch command)")
 )
 
 func main() {
@@ -28,6 +57,11 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  activate <partition-id> Activate a fabric partition\n")
 		fmt.Fprintf(os.Stderr, "  deactivate <partition-id> Deactivate a fabric partition\n")
 		fmt.Fprintf(os.Stderr, "  info                    Show FM connection information\n")
+		fmt.Fprintf(os.Stderr, "  dra                     Run as a Kubernetes DRA kubelet plugin\n")
+		fmt.Fprintf(os.Stderr, "  serve                   Run as a long-lived daemon with gRPC/REST/metrics\n")
+		fmt.Fprintf(os.Stderr, "  plan -f <file>          Show the changes needed to converge on a spec\n")
+		fmt.Fprintf(os.Stderr, "  apply -f <file>         Converge fabric partitions on a declarative spec\n")
+		fmt.Fprintf(os.Stderr, "  watch                   Stream partition state transitions as JSON lines\n")
 		fmt.Fprintf(os.Stderr, "\nOptions:\n")
 		flag.PrintDefaults()
 	}
@@ -47,6 +81,25 @@ func main() {
 		return
 	}
 
+	// plan and apply take their flags after the command name, as in
+	// "fmctl apply -f partitions.hcl".
+	if command == "plan" || command == "apply" {
+		flag.CommandLine.Parse(flag.Args()[1:])
+	}
+
+	// dra manages its own FM connection internally, for the lifetime of the
+	// kubelet plugin, so it bypasses the shared connect/disconnect below.
+	if command == "dra" {
+		cmdDRA()
+		return
+	}
+
+	// serve, like dra, owns its FM connection for the lifetime of the daemon.
+	if command == "serve" {
+		cmdServe()
+		return
+	}
+
 	// Initialize FM library
 	if ret := fmsdk.FMLibInit(); ret != fmsdk.FM_ST_SUCCESS {
 		if *verbose {
@@ -89,6 +142,12 @@ func main() {
 			os.Exit(1)
 		}
 		cmdActivate(handle, uint32(partitionID))
+	case "watch":
+		cmdWatch(handle)
+	case "plan":
+		cmdPlan(handle)
+	case "apply":
+		cmdApply(handle)
 	case "deactivate":
 		if flag.NArg() < 2 {
 			fmt.Fprintf(os.Stderr, "Error: deactivate command requires partition-id argument\n")
@@ -123,12 +182,60 @@ func connectToFM() (fmsdk.FMHandle, fmsdk.FMReturn) {
 	return fmsdk.FMConnect(params)
 }
 
+// enrichPartitions fills empty UUID/PCIBusID fields across partitions by
+// resolving each GPU's PhysicalID via NVML. It is a no-op unless --enrich is
+// set, and fails open (logging a warning in verbose mode) since enrichment
+// is always optional. The returned map carries the extra NVML-only fields
+// (board serial, MIG capability) that FMFabricPartitionGpuInfo has no room
+// for, keyed by PhysicalID.
+func enrichPartitions(partitions []fmsdk.FMPartitionInfo) map[uint32]nvmlx.GPUDetails {
+	if !*enrich {
+		return nil
+	}
+
+	enricher, err := nvmlx.Open()
+	if err != nil {
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "Warning: --enrich: %v\n", err)
+		}
+		return nil
+	}
+	defer enricher.Close()
+
+	details, err := enricher.Resolve()
+	if err != nil {
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "Warning: --enrich: %v\n", err)
+		}
+		return nil
+	}
+
+	for i := range partitions {
+		for j := range partitions[i].GPUInfo {
+			gpu := &partitions[i].GPUInfo[j]
+			d, ok := details[gpu.PhysicalID]
+			if !ok {
+				continue
+			}
+			if gpu.UUID == "" {
+				gpu.UUID = d.UUID
+			}
+			if gpu.PCIBusID == "" {
+				gpu.PCIBusID = d.PCIBusID
+			}
+		}
+	}
+
+	return details
+}
+
 func cmdList(handle fmsdk.FMHandle) {
 	partitions, ret := fmsdk.FMGetSupportedFabricPartitions(handle)
 	if ret != fmsdk.FM_ST_SUCCESS {
 		fmt.Fprintf(os.Stderr, "Error: Failed to get fabric partitions: %v\n", ret)
 		os.Exit(1)
 	}
+	enrichPartitions(partitions)
 
 	if *jsonOutput {
 		data, err := json.MarshalIndent(partitions, "", "  ")
@@ -176,6 +283,8 @@ func cmdStatus(handle fmsdk.FMHandle, partitionID uint32) {
 		os.Exit(1)
 	}
 
+	enrichDetails := enrichPartitions(partitions)
+
 	var partition *fmsdk.FMPartitionInfo
 	for i := range partitions {
 		if partitions[i].PartitionID == partitionID {
@@ -205,8 +314,14 @@ func cmdStatus(handle fmsdk.FMHandle, partitionID uint32) {
 
 	if len(partition.GPUInfo) > 0 {
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "PHYSICAL ID\tUUID\tPCI BUS ID\tNVLINKS (Available/Max)\tRATE (MB/s)")
-		fmt.Fprintln(w, "-----------\t----\t----------\t-----------------------\t-----------")
+		header := "PHYSICAL ID\tUUID\tPCI BUS ID\tNVLINKS (Available/Max)\tRATE (MB/s)"
+		separator := "-----------\t----\t----------\t-----------------------\t-----------"
+		if *enrich {
+			header += "\tBOARD SERIAL\tMIG CAPABLE"
+			separator += "\t------------\t-----------"
+		}
+		fmt.Fprintln(w, header)
+		fmt.Fprintln(w, separator)
 
 		for _, gpu := range partition.GPUInfo {
 			uuid := gpu.UUID
@@ -218,13 +333,23 @@ func cmdStatus(handle fmsdk.FMHandle, partitionID uint32) {
 				pciBusID = "N/A"
 			}
 
-			fmt.Fprintf(w, "%d\t%s\t%s\t%d/%d\t%d\n",
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d/%d\t%d",
 				gpu.PhysicalID,
 				uuid,
 				pciBusID,
 				gpu.NumNvLinksAvailable,
 				gpu.MaxNumNvLinks,
 				gpu.NvlinkLineRateMBps)
+
+			if *enrich {
+				d := enrichDetails[gpu.PhysicalID]
+				serial := d.BoardSerial
+				if serial == "" {
+					serial = "N/A"
+				}
+				fmt.Fprintf(w, "\t%s\t%v", serial, d.MIGCapable)
+			}
+			fmt.Fprintln(w)
 		}
 		w.Flush()
 	}
@@ -278,6 +403,161 @@ func cmdDeactivate(handle fmsdk.FMHandle, partitionID uint32) {
 	}
 }
 
+func cmdDRA() {
+	nodeName := *draNodeName
+	if nodeName == "" {
+		if hostname, err := os.Hostname(); err == nil {
+			nodeName = hostname
+		}
+	}
+	if nodeName == "" {
+		fmt.Fprintf(os.Stderr, "Error: dra command requires -dra-node-name (could not determine hostname)\n")
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := dra.Config{
+		NodeName:          nodeName,
+		Address:           *address,
+		TimeoutMs:         uint32(*timeout),
+		AddressIsUnixSock: strings.HasSuffix(*address, ".sock"),
+		KubeletPluginDir:  *draKubeletPluginDir,
+		CDIRoot:           *draCDIRoot,
+		PollInterval:      *draPollInterval,
+	}
+
+	if *verbose {
+		fmt.Printf("Starting DRA plugin %q for node %q\n", dra.DriverName, nodeName)
+	}
+
+	if err := dra.Run(ctx, cfg); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "Error: dra plugin exited: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func cmdWatch(handle fmsdk.FMHandle) {
+	sinks := []fmwatch.Sink{fmwatch.NewWriterSink(os.Stdout)}
+
+	if *watchWebhook != "" {
+		sinks = append(sinks, fmwatch.NewWebhookSink(*watchWebhook))
+	}
+	if *watchSyslog {
+		sink, err := fmwatch.NewSyslogSink()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	w := &fmwatch.Watcher{Handle: handle, Interval: *watchInterval, Sinks: sinks}
+	if err := w.Run(ctx); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "Error: watch exited: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func cmdPlan(handle fmsdk.FMHandle) {
+	plan, err := fmplan.PlanFromLive(handle, *applyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		data, _ := json.MarshalIndent(plan, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	for _, violation := range plan.Violations {
+		fmt.Printf("! %s\n", violation)
+	}
+	if plan.NoOp() {
+		fmt.Println("No changes. Fabric partitions already match the spec.")
+		return
+	}
+	for _, action := range plan.Actions {
+		fmt.Printf("~ %s\n", action)
+	}
+}
+
+func cmdApply(handle fmsdk.FMHandle) {
+	if *applyWatch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := fmplan.Watch(ctx, handle, fmplan.WatchOptions{
+			SpecPath:     *applyFile,
+			PollInterval: *applyPollInterval,
+		}); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	plan, err := fmplan.PlanFromLive(handle, *applyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(plan.Violations) > 0 {
+		for _, violation := range plan.Violations {
+			fmt.Fprintf(os.Stderr, "Error: constraint violation: %s\n", violation)
+		}
+		os.Exit(1)
+	}
+
+	if plan.NoOp() {
+		fmt.Println("No changes. Fabric partitions already match the spec.")
+		return
+	}
+
+	for _, action := range plan.Actions {
+		if *verbose {
+			fmt.Printf("Applying: %s\n", action)
+		}
+	}
+	if err := fmplan.Apply(handle, plan); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Applied %d change(s).\n", len(plan.Actions))
+}
+
+func cmdServe() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg := fmserve.Config{
+		Address:           *address,
+		TimeoutMs:         uint32(*timeout),
+		AddressIsUnixSock: strings.HasSuffix(*address, ".sock"),
+		GRPCAddr:          *serveGRPCAddr,
+		HTTPAddr:          *serveHTTPAddr,
+		MetricsAddr:       *serveMetricsAddr,
+		PollInterval:      *servePollInterval,
+	}
+
+	if *verbose {
+		fmt.Printf("Starting fmctl serve: grpc=%s http=%s metrics=%s poll=%s\n",
+			cfg.GRPCAddr, cfg.HTTPAddr, cfg.MetricsAddr, cfg.PollInterval)
+	}
+
+	if err := fmserve.Run(ctx, cfg); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "Error: serve exited: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func cmdInfo() {
 	info := map[string]interface{}{
 		"address":        *address,
@@ -0,0 +1,96 @@
+package dra
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"k8s.io/dynamic-resource-allocation/kubeletplugin"
+	cdiapi "tags.cncf.io/container-device-interface/pkg/cdi"
+	cdispec "tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+// cdiVendor/cdiClass identify the CDI kind this plugin registers devices
+// under, e.g. "fabricmanager.lambdalabs.com/partition".
+const (
+	cdiVendor = "fabricmanager.lambdalabs.com"
+	cdiClass  = "partition"
+)
+
+// cdiHandler writes and removes the CDI spec file backing an activated
+// fabric partition so the container runtime can bind its GPUs in.
+type cdiHandler struct {
+	root string
+}
+
+func newCDIHandler(root string) *cdiHandler {
+	return &cdiHandler{root: root}
+}
+
+// write renders a CDI spec binding partitionID's GPUs into the container and
+// returns the device reference for use in a kubeletplugin.PrepareResult.
+func (h *cdiHandler) write(partitionID uint32, gpus []fmsdk.FMFabricPartitionGpuInfo) ([]kubeletplugin.Device, error) {
+	deviceName := partitionDeviceName(partitionID)
+	kind := cdiVendor + "/" + cdiClass
+
+	deviceNodes := make([]*cdispec.DeviceNode, len(gpus))
+	for i, gpu := range gpus {
+		deviceNodes[i] = &cdispec.DeviceNode{Path: gpuDevicePath(gpu.PhysicalID)}
+	}
+
+	spec := &cdispec.Spec{
+		Version: "0.6.0",
+		Kind:    kind,
+		Devices: []cdispec.Device{
+			{
+				Name: deviceName,
+				ContainerEdits: cdispec.ContainerEdits{
+					Env: []string{
+						fmt.Sprintf("FM_PARTITION_ID=%d", partitionID),
+					},
+					DeviceNodes: deviceNodes,
+				},
+			},
+		},
+	}
+
+	// Named after the partition so remove can find the same file again.
+	if err := cdiapi.WriteSpec(spec, h.specPath(deviceName)); err != nil {
+		return nil, fmt.Errorf("write CDI spec for partition %d: %w", partitionID, err)
+	}
+
+	return []kubeletplugin.Device{
+		{
+			Requests:     nil,
+			PoolName:     deviceName,
+			DeviceName:   deviceName,
+			CDIDeviceIDs: []string{kind + "=" + deviceName},
+		},
+	}, nil
+}
+
+// remove deletes the CDI spec previously written for partitionID.
+func (h *cdiHandler) remove(partitionID uint32) error {
+	if err := os.Remove(h.specPath(partitionDeviceName(partitionID))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove CDI spec for partition %d: %w", partitionID, err)
+	}
+	return nil
+}
+
+func (h *cdiHandler) specPath(name string) string {
+	return h.root + "/" + strings.ReplaceAll(name, "/", "_") + ".json"
+}
+
+func partitionDeviceName(partitionID uint32) string {
+	return "partition-" + strconv.FormatUint(uint64(partitionID), 10)
+}
+
+// gpuDevicePath returns /dev/nvidia<physicalID>: the GPU's physicalId
+// (NVIDIA's module ID) is used directly as the device node number and is not
+// translated to the driver's device index.
+func gpuDevicePath(physicalID uint32) string {
+	return "/dev/nvidia" + strconv.FormatUint(uint64(physicalID), 10)
+}
@@ -0,0 +1,34 @@
+package dra
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/dynamic-resource-allocation/kubeletplugin"
+)
+
+// partitionIDFromSelectedDevices maps the single device kubelet selected for
+// a claim back to the fabric partition ID encoded in its name.
+func partitionIDFromSelectedDevices(devices []kubeletplugin.Device) (uint32, error) {
+	if len(devices) != 1 {
+		return 0, fmt.Errorf("expected exactly one selected device, got %d", len(devices))
+	}
+
+	name := devices[0].DeviceName
+	id, ok := strings.CutPrefix(name, "partition-")
+	if !ok {
+		return 0, fmt.Errorf("unrecognized device name %q", name)
+	}
+
+	partitionID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parse partition ID from device name %q: %w", name, err)
+	}
+
+	return uint32(partitionID), nil
+}
+
+func joinStrings(ss []string) string {
+	return strings.Join(ss, ",")
+}
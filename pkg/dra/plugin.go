@@ -0,0 +1,274 @@
+// Package dra implements fmctl as a Kubernetes Dynamic Resource Allocation
+// (DRA) kubelet plugin for NVSwitch fabric partitions. It mirrors the shape
+// of NVIDIA's k8s-dra-driver: partitions reported by
+// FMGetSupportedFabricPartitions are published as allocatable devices in a
+// ResourceSlice, and NodePrepareResources/NodeUnprepareResources activate and
+// deactivate the selected partition via the FM SDK.
+package dra
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	resourceapi "k8s.io/api/resource/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/dynamic-resource-allocation/kubeletplugin"
+	"k8s.io/dynamic-resource-allocation/resourceslice"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+	"github.com/lambdalabs/fmctl/pkg/fmwatch"
+)
+
+// DriverName is the DRA driver name registered with kubelet and used as the
+// API group for the resource claims this plugin satisfies.
+const DriverName = "fabricmanager.lambdalabs.com"
+
+// Config controls how the DRA plugin connects to Fabric Manager and where it
+// registers itself with kubelet.
+type Config struct {
+	NodeName          string
+	Address           string
+	TimeoutMs         uint32
+	AddressIsUnixSock bool
+	KubeletPluginDir  string
+	CDIRoot           string
+
+	// PollInterval is how often the plugin refreshes partition state from FM
+	// and republishes the ResourceSlice, analogous to fmserve.Config's knob
+	// of the same name.
+	PollInterval time.Duration
+}
+
+// Plugin implements kubeletplugin.DRAPlugin for fabric partitions. It holds a
+// single long-lived FM connection, shared between ResourceSlice publishing
+// and prepare/unprepare calls.
+type Plugin struct {
+	cfg    Config
+	handle fmsdk.FMHandle
+
+	helper *kubeletplugin.Helper
+	cdi    *cdiHandler
+	slices *resourceslice.Controller
+
+	// prevPoll is the partition snapshot from the previous poll, used to
+	// diff against the current one so poll() can log fabric transitions.
+	prevPoll []fmsdk.FMPartitionInfo
+}
+
+// Run initializes Fabric Manager, registers the kubelet plugin, and blocks
+// serving NodePrepareResources/NodeUnprepareResources until ctx is canceled.
+func Run(ctx context.Context, cfg Config) error {
+	if ret := fmsdk.FMLibInit(); ret != fmsdk.FM_ST_SUCCESS {
+		log.Printf("dra: FMLibInit returned %v (continuing, FM may already be initialized)", ret)
+	}
+	defer fmsdk.FMLibShutdown()
+
+	handle, ret := fmsdk.FMConnect(fmsdk.FMConnectParams{
+		Version:             1,
+		AddressInfo:         cfg.Address,
+		TimeoutMs:           cfg.TimeoutMs,
+		AddressIsUnixSocket: cfg.AddressIsUnixSock,
+	})
+	if ret != fmsdk.FM_ST_SUCCESS {
+		return fmt.Errorf("dra: connect to Fabric Manager: %v", ret)
+	}
+	defer fmsdk.FMDisconnect(handle)
+
+	p := &Plugin{
+		cfg:    cfg,
+		handle: handle,
+		cdi:    newCDIHandler(cfg.CDIRoot),
+	}
+
+	helper, err := kubeletplugin.Start(
+		ctx,
+		p,
+		kubeletplugin.DriverName(DriverName),
+		kubeletplugin.NodeName(cfg.NodeName),
+		kubeletplugin.KubeletPluginSocketPath(cfg.KubeletPluginDir),
+	)
+	if err != nil {
+		return fmt.Errorf("dra: start kubelet plugin: %w", err)
+	}
+	p.helper = helper
+	defer helper.Stop()
+
+	resources := p.currentResources()
+	slices, err := resourceslice.StartController(ctx, resourceslice.Options{
+		DriverName: DriverName,
+		NodeName:   cfg.NodeName,
+		KubeClient: helper.KubeClient(),
+		Resources:  &resources,
+	})
+	if err != nil {
+		return fmt.Errorf("dra: start resourceslice controller: %w", err)
+	}
+	p.slices = slices
+	defer slices.Stop()
+
+	p.pollLoop(ctx)
+	return ctx.Err()
+}
+
+// pollLoop republishes the ResourceSlice every PollInterval until ctx is
+// canceled, so partitions that appear, disappear, or degrade after startup
+// are reflected in kubelet's advertised device inventory instead of going
+// stale for the life of the plugin process.
+func (p *Plugin) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.poll()
+		}
+	}
+}
+
+// poll refreshes the ResourceSlice from the current FM state and logs any
+// fabric transitions (partition appeared/disappeared/activated/deactivated,
+// nvlinks_degraded/recovered) since the previous poll, reusing the same
+// diffing engine fmctl watch and fmserve are built on.
+func (p *Plugin) poll() {
+	partitions, ret := fmsdk.FMGetSupportedFabricPartitions(p.handle)
+	if ret != fmsdk.FM_ST_SUCCESS {
+		log.Printf("dra: poll FMGetSupportedFabricPartitions: %v", ret)
+		return
+	}
+
+	for _, event := range fmwatch.Diff(p.prevPoll, partitions) {
+		log.Printf("dra: %s partition %d", event.Type, event.PartitionID)
+	}
+	p.prevPoll = partitions
+
+	resources := resourcesFromPartitions(p.cfg.NodeName, partitions)
+	p.slices.Update(&resources)
+}
+
+// currentResources queries Fabric Manager and builds the ResourceSlice device
+// pool describing every supported partition.
+func (p *Plugin) currentResources() resourceslice.DriverResources {
+	partitions, ret := fmsdk.FMGetSupportedFabricPartitions(p.handle)
+	if ret != fmsdk.FM_ST_SUCCESS {
+		log.Printf("dra: FMGetSupportedFabricPartitions: %v", ret)
+		return resourceslice.DriverResources{}
+	}
+	p.prevPoll = partitions
+	return resourcesFromPartitions(p.cfg.NodeName, partitions)
+}
+
+// resourcesFromPartitions builds the ResourceSlice device pool describing
+// every supported partition, shared by currentResources (initial snapshot)
+// and poll (periodic republish).
+func resourcesFromPartitions(nodeName string, partitions []fmsdk.FMPartitionInfo) resourceslice.DriverResources {
+	devices := make([]resourceapi.Device, 0, len(partitions))
+	for _, part := range partitions {
+		var nvlinkRate, nvlinksAvail int64
+		physIDs := make([]string, 0, len(part.GPUInfo))
+		for _, gpu := range part.GPUInfo {
+			nvlinkRate = int64(gpu.NvlinkLineRateMBps)
+			nvlinksAvail += int64(gpu.NumNvLinksAvailable)
+			physIDs = append(physIDs, fmt.Sprintf("%d", gpu.PhysicalID))
+		}
+
+		devices = append(devices, resourceapi.Device{
+			Name: partitionDeviceName(part.PartitionID),
+			Basic: &resourceapi.BasicDevice{
+				Attributes: map[resourceapi.QualifiedName]resourceapi.DeviceAttribute{
+					"numGpus":            {IntValue: int64Ptr(int64(part.NumGpus))},
+					"nvlinkLineRateMBps": {IntValue: int64Ptr(nvlinkRate)},
+					"nvlinksAvailable":   {IntValue: int64Ptr(nvlinksAvail)},
+					"physicalIds":        {StringValue: strPtr(joinStrings(physIDs))},
+				},
+			},
+		})
+	}
+
+	return resourceslice.DriverResources{
+		Pools: map[string]resourceslice.Pool{
+			nodeName: {
+				Slices: []resourceslice.Slice{{Devices: devices}},
+			},
+		},
+	}
+}
+
+// NodePrepareResources activates the fabric partition backing each requested
+// claim and returns CDI device edits binding its GPUs into the container.
+func (p *Plugin) NodePrepareResources(ctx context.Context, claims []*kubeletplugin.NodePrepareResourceClaim) (map[types.UID]kubeletplugin.PrepareResult, error) {
+	results := make(map[types.UID]kubeletplugin.PrepareResult, len(claims))
+
+	partitions, ret := fmsdk.FMGetSupportedFabricPartitions(p.handle)
+	if ret != fmsdk.FM_ST_SUCCESS {
+		return nil, fmt.Errorf("dra: FMGetSupportedFabricPartitions: %v", ret)
+	}
+	gpusByPartition := make(map[uint32][]fmsdk.FMFabricPartitionGpuInfo, len(partitions))
+	for _, part := range partitions {
+		gpusByPartition[part.PartitionID] = part.GPUInfo
+	}
+
+	for _, claim := range claims {
+		partitionID, err := partitionIDFromSelectedDevices(claim.Devices)
+		if err != nil {
+			results[claim.UID] = kubeletplugin.PrepareResult{Err: err}
+			continue
+		}
+
+		gpus, ok := gpusByPartition[partitionID]
+		if !ok {
+			results[claim.UID] = kubeletplugin.PrepareResult{
+				Err: fmt.Errorf("partition %d not found in current FM state", partitionID),
+			}
+			continue
+		}
+
+		if ret := fmsdk.FMActivateFabricPartition(p.handle, partitionID); ret != fmsdk.FM_ST_SUCCESS {
+			results[claim.UID] = kubeletplugin.PrepareResult{
+				Err: fmt.Errorf("activate partition %d: %v", partitionID, ret),
+			}
+			continue
+		}
+
+		devices, err := p.cdi.write(partitionID, gpus)
+		if err != nil {
+			fmsdk.FMDeactivateFabricPartition(p.handle, partitionID)
+			results[claim.UID] = kubeletplugin.PrepareResult{Err: err}
+			continue
+		}
+
+		results[claim.UID] = kubeletplugin.PrepareResult{Devices: devices}
+	}
+
+	return results, nil
+}
+
+// NodeUnprepareResources deactivates the fabric partition backing each claim
+// and removes its generated CDI spec.
+func (p *Plugin) NodeUnprepareResources(ctx context.Context, claims []*kubeletplugin.NodeUnprepareResourceClaim) (map[types.UID]error, error) {
+	results := make(map[types.UID]error, len(claims))
+
+	for _, claim := range claims {
+		partitionID, err := partitionIDFromSelectedDevices(claim.Devices)
+		if err != nil {
+			results[claim.UID] = err
+			continue
+		}
+
+		if ret := fmsdk.FMDeactivateFabricPartition(p.handle, partitionID); ret != fmsdk.FM_ST_SUCCESS {
+			results[claim.UID] = fmt.Errorf("deactivate partition %d: %v", partitionID, ret)
+			continue
+		}
+
+		results[claim.UID] = p.cdi.remove(partitionID)
+	}
+
+	return results, nil
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+func strPtr(v string) *string { return &v }
@@ -0,0 +1,45 @@
+package fmplan
+
+import (
+	"fmt"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+// Apply issues the activate/deactivate calls in plan against handle, in
+// order, stopping at the first failure.
+func Apply(handle fmsdk.FMHandle, plan *Plan) error {
+	for _, action := range plan.Actions {
+		var ret fmsdk.FMReturn
+		switch action.Kind {
+		case ActionActivate:
+			ret = fmsdk.FMActivateFabricPartition(handle, action.PartitionID)
+		case ActionDeactivate:
+			ret = fmsdk.FMDeactivateFabricPartition(handle, action.PartitionID)
+		default:
+			return fmt.Errorf("fmplan: unknown action kind %q", action.Kind)
+		}
+
+		if ret != fmsdk.FM_ST_SUCCESS {
+			return fmt.Errorf("fmplan: %s: %v", action, ret)
+		}
+	}
+
+	return nil
+}
+
+// PlanFromLive loads spec from path and computes a Plan against the current
+// FM state on handle.
+func PlanFromLive(handle fmsdk.FMHandle, path string) (*Plan, error) {
+	spec, err := LoadSpec(path)
+	if err != nil {
+		return nil, err
+	}
+
+	live, ret := fmsdk.FMGetSupportedFabricPartitions(handle)
+	if ret != fmsdk.FM_ST_SUCCESS {
+		return nil, fmt.Errorf("fmplan: FMGetSupportedFabricPartitions: %v", ret)
+	}
+
+	return Compute(spec, live), nil
+}
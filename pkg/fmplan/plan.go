@@ -0,0 +1,113 @@
+package fmplan
+
+import (
+	"fmt"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+// ActionKind is the FM call an Action will issue.
+type ActionKind string
+
+const (
+	ActionActivate   ActionKind = "activate"
+	ActionDeactivate ActionKind = "deactivate"
+)
+
+// Action is a single activate/deactivate call needed to converge a partition
+// on its desired state.
+type Action struct {
+	PartitionID uint32
+	Kind        ActionKind
+}
+
+func (a Action) String() string {
+	return fmt.Sprintf("%s partition %d", a.Kind, a.PartitionID)
+}
+
+// Plan is the diff between a Spec and the live FM state: the actions needed
+// to converge, plus any constraint violations that should block applying it.
+type Plan struct {
+	Actions    []Action
+	Violations []string
+}
+
+// NoOp reports whether the plan has nothing to do and nothing to block on.
+func (p *Plan) NoOp() bool {
+	return len(p.Actions) == 0 && len(p.Violations) == 0
+}
+
+// Compute diffs spec against the live partitions reported by FM and returns
+// the minimum set of activate/deactivate calls to converge, along with any
+// constraint violations found in the current (pre-apply) state.
+func Compute(spec *Spec, live []fmsdk.FMPartitionInfo) *Plan {
+	plan := &Plan{}
+
+	for _, partition := range live {
+		if spec.isIgnored(partition.PartitionID) {
+			continue
+		}
+
+		desired, ok := spec.desiredState(partition.PartitionID)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case desired == StateActive && !partition.IsActive:
+			plan.Actions = append(plan.Actions, Action{PartitionID: partition.PartitionID, Kind: ActionActivate})
+		case desired == StateInactive && partition.IsActive:
+			plan.Actions = append(plan.Actions, Action{PartitionID: partition.PartitionID, Kind: ActionDeactivate})
+		}
+	}
+
+	plan.Violations = checkConstraints(spec, live)
+
+	return plan
+}
+
+// checkConstraints validates live partition state against spec.Constraints,
+// returning a human-readable violation message for each unmet constraint.
+func checkConstraints(spec *Spec, live []fmsdk.FMPartitionInfo) []string {
+	if spec.Constraints == nil {
+		return nil
+	}
+
+	var violations []string
+
+	for _, partition := range live {
+		if spec.isIgnored(partition.PartitionID) {
+			continue
+		}
+		if _, ok := spec.desiredState(partition.PartitionID); !ok {
+			continue
+		}
+
+		if min := spec.Constraints.MinNvLinksAvailable; min != nil {
+			var available uint32
+			for _, gpu := range partition.GPUInfo {
+				available += gpu.NumNvLinksAvailable
+			}
+			if available < *min {
+				violations = append(violations, fmt.Sprintf(
+					"partition %d: nvlinks_available %d below min_nvlinks_available %d",
+					partition.PartitionID, available, *min))
+			}
+		}
+
+		if ids := spec.Constraints.RequireGPUPhysicalIDs; len(ids) > 0 {
+			have := make(map[uint32]bool, len(partition.GPUInfo))
+			for _, gpu := range partition.GPUInfo {
+				have[gpu.PhysicalID] = true
+			}
+			for _, want := range ids {
+				if !have[want] {
+					violations = append(violations, fmt.Sprintf(
+						"partition %d: missing required GPU physical ID %d", partition.PartitionID, want))
+				}
+			}
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,105 @@
+package fmplan
+
+import (
+	"testing"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+func gpu(physicalID, nvlinksAvail, nvlinksMax uint32) fmsdk.FMFabricPartitionGpuInfo {
+	return fmsdk.FMFabricPartitionGpuInfo{
+		PhysicalID:          physicalID,
+		NumNvLinksAvailable: nvlinksAvail,
+		MaxNumNvLinks:       nvlinksMax,
+	}
+}
+
+func TestComputeActions(t *testing.T) {
+	spec := &Spec{
+		Partitions: []PartitionSpec{
+			{ID: 1, State: StateActive},
+			{ID: 2, State: StateInactive},
+			{ID: 3, State: StateActive},
+			{ID: 4, State: StateActive},
+		},
+		IgnoredPartitionIDs: []uint32{4},
+	}
+	live := []fmsdk.FMPartitionInfo{
+		{PartitionID: 1, IsActive: false},
+		{PartitionID: 2, IsActive: true},
+		{PartitionID: 3, IsActive: true},
+		{PartitionID: 4, IsActive: false}, // ignored, would otherwise need activation
+		{PartitionID: 5, IsActive: true},  // not in spec, untouched
+	}
+
+	plan := Compute(spec, live)
+
+	want := map[uint32]ActionKind{1: ActionActivate, 2: ActionDeactivate}
+	if len(plan.Actions) != len(want) {
+		t.Fatalf("Compute actions = %+v, want %d actions matching %v", plan.Actions, len(want), want)
+	}
+	for _, a := range plan.Actions {
+		if want[a.PartitionID] != a.Kind {
+			t.Errorf("action for partition %d = %s, want %s", a.PartitionID, a.Kind, want[a.PartitionID])
+		}
+	}
+}
+
+func TestComputeNoOpWhenConverged(t *testing.T) {
+	spec := &Spec{Partitions: []PartitionSpec{{ID: 1, State: StateActive}}}
+	live := []fmsdk.FMPartitionInfo{{PartitionID: 1, IsActive: true}}
+
+	plan := Compute(spec, live)
+	if !plan.NoOp() {
+		t.Fatalf("Compute with converged state = %+v, want NoOp", plan)
+	}
+}
+
+func TestCheckConstraintsMinNvLinksAvailable(t *testing.T) {
+	min := uint32(20)
+	spec := &Spec{
+		Partitions:  []PartitionSpec{{ID: 1, State: StateActive}},
+		Constraints: &Constraints{MinNvLinksAvailable: &min},
+	}
+	live := []fmsdk.FMPartitionInfo{
+		{PartitionID: 1, GPUInfo: []fmsdk.FMFabricPartitionGpuInfo{gpu(0, 12, 18)}},
+	}
+
+	plan := Compute(spec, live)
+	if len(plan.Violations) != 1 {
+		t.Fatalf("Violations = %v, want exactly one min_nvlinks_available violation", plan.Violations)
+	}
+}
+
+func TestCheckConstraintsRequireGPUPhysicalIDs(t *testing.T) {
+	spec := &Spec{
+		Partitions:  []PartitionSpec{{ID: 1, State: StateActive}},
+		Constraints: &Constraints{RequireGPUPhysicalIDs: []uint32{0, 1}},
+	}
+	live := []fmsdk.FMPartitionInfo{
+		{PartitionID: 1, GPUInfo: []fmsdk.FMFabricPartitionGpuInfo{gpu(0, 18, 18)}},
+	}
+
+	plan := Compute(spec, live)
+	if len(plan.Violations) != 1 {
+		t.Fatalf("Violations = %v, want exactly one missing GPU physical ID violation", plan.Violations)
+	}
+}
+
+func TestCheckConstraintsIgnoresUnspecifiedPartitions(t *testing.T) {
+	min := uint32(20)
+	spec := &Spec{
+		Partitions:  []PartitionSpec{{ID: 1, State: StateActive}},
+		Constraints: &Constraints{MinNvLinksAvailable: &min},
+	}
+	live := []fmsdk.FMPartitionInfo{
+		// Partition 2 isn't in spec.Partitions, so it should be skipped even
+		// though it violates the constraint.
+		{PartitionID: 2, GPUInfo: []fmsdk.FMFabricPartitionGpuInfo{gpu(0, 4, 18)}},
+	}
+
+	plan := Compute(spec, live)
+	if len(plan.Violations) != 0 {
+		t.Fatalf("Violations = %v, want none for a partition outside the spec", plan.Violations)
+	}
+}
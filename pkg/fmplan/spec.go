@@ -0,0 +1,76 @@
+// Package fmplan implements declarative reconciliation of fabric partitions
+// from an HCL spec file, in the style of the HCL-driven, idempotent config
+// used by Nomad's Nvidia device plugin: a desired state is diffed against
+// FMGetSupportedFabricPartitions and the minimum set of activate/deactivate
+// calls is issued to converge.
+package fmplan
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2/hclsimple"
+)
+
+// PartitionState is the desired state of a partition in a Spec.
+type PartitionState string
+
+const (
+	StateActive   PartitionState = "active"
+	StateInactive PartitionState = "inactive"
+)
+
+// PartitionSpec declares the desired state of a single partition block.
+type PartitionSpec struct {
+	ID    uint32         `hcl:"id,label"`
+	State PartitionState `hcl:"state"`
+}
+
+// Constraints validates the fabric topology before any changes are applied.
+type Constraints struct {
+	MinNvLinksAvailable   *uint32  `hcl:"min_nvlinks_available,optional"`
+	RequireGPUPhysicalIDs []uint32 `hcl:"require_gpu_physical_ids,optional"`
+}
+
+// Spec is the top-level decoded form of a partitions.hcl file.
+type Spec struct {
+	Partitions          []PartitionSpec `hcl:"partition,block"`
+	IgnoredPartitionIDs []uint32        `hcl:"ignored_partition_ids,optional"`
+	Constraints         *Constraints    `hcl:"constraints,block"`
+}
+
+// LoadSpec decodes an HCL spec file into a Spec.
+func LoadSpec(path string) (*Spec, error) {
+	var spec Spec
+	if err := hclsimple.DecodeFile(path, nil, &spec); err != nil {
+		return nil, fmt.Errorf("fmplan: decode %s: %w", path, err)
+	}
+
+	for _, p := range spec.Partitions {
+		if p.State != StateActive && p.State != StateInactive {
+			return nil, fmt.Errorf("fmplan: partition %d: invalid state %q (want %q or %q)",
+				p.ID, p.State, StateActive, StateInactive)
+		}
+	}
+
+	return &spec, nil
+}
+
+// isIgnored reports whether partitionID is listed in IgnoredPartitionIDs.
+func (s *Spec) isIgnored(partitionID uint32) bool {
+	for _, id := range s.IgnoredPartitionIDs {
+		if id == partitionID {
+			return true
+		}
+	}
+	return false
+}
+
+// desiredState returns the spec's desired state for partitionID, if any.
+func (s *Spec) desiredState(partitionID uint32) (PartitionState, bool) {
+	for _, p := range s.Partitions {
+		if p.ID == partitionID {
+			return p.State, true
+		}
+	}
+	return "", false
+}
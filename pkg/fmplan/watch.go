@@ -0,0 +1,81 @@
+package fmplan
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+// WatchOptions controls fmctl apply --watch.
+type WatchOptions struct {
+	SpecPath     string
+	PollInterval time.Duration
+}
+
+// Watch reconciles SpecPath against live FM state whenever the file changes
+// or PollInterval elapses, whichever comes first, until ctx is canceled.
+func Watch(ctx context.Context, handle fmsdk.FMHandle, opts WatchOptions) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(opts.SpecPath); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	reconcile := func() {
+		plan, err := PlanFromLive(handle, opts.SpecPath)
+		if err != nil {
+			log.Printf("fmplan: watch: %v", err)
+			return
+		}
+		if plan.NoOp() {
+			return
+		}
+		for _, violation := range plan.Violations {
+			log.Printf("fmplan: watch: constraint violation: %s", violation)
+		}
+		if len(plan.Violations) > 0 {
+			log.Printf("fmplan: watch: refusing to apply while constraints are violated")
+			return
+		}
+		for _, action := range plan.Actions {
+			log.Printf("fmplan: watch: applying %s", action)
+		}
+		if err := Apply(handle, plan); err != nil {
+			log.Printf("fmplan: watch: apply failed: %v", err)
+		}
+	}
+
+	reconcile()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			reconcile()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				reconcile()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("fmplan: watch: fsnotify error: %v", err)
+		}
+	}
+}
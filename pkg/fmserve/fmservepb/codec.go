@@ -0,0 +1,43 @@
+package fmservepb
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/encoding"
+)
+
+// wireCodec implements encoding.Codec on top of github.com/golang/protobuf's
+// reflection-based Marshal/Unmarshal. Unlike grpc-go's built-in "proto"
+// codec, that package's legacy support works directly off the "protobuf"
+// struct tags on the message types in fmserve.pb.go — it doesn't require a
+// compiled file descriptor, so it marshals real protobuf wire bytes without
+// needing a protoc/protoc-gen-go run. Name returns "proto" so it's selected
+// for the default "application/grpc" content-type; it's installed with
+// grpc.ForceServerCodec rather than encoding.RegisterCodec, since relying on
+// init-order to win the registry race against grpc-go's own "proto" codec
+// isn't guaranteed.
+type wireCodec struct{}
+
+// Codec returns the grpc codec fmctl serve installs on its gRPC server.
+func Codec() encoding.Codec { return wireCodec{} }
+
+func (wireCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("fmservepb: marshal: %T is not a proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (wireCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("fmservepb: unmarshal: %T is not a proto.Message", v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+func (wireCodec) Name() string {
+	return "proto"
+}
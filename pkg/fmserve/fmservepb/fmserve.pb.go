@@ -0,0 +1,80 @@
+// Package fmservepb holds the wire types for proto/fmserve.proto. This
+// build has no protoc / protoc-gen-go toolchain available, so these structs
+// are hand-maintained rather than generated — but they implement
+// proto.Message (Reset/String/ProtoMessage) the same way protoc-gen-go's
+// pre-APIv2 output did, so github.com/golang/protobuf/proto can marshal
+// them to real protobuf wire bytes by reflecting over the "protobuf" struct
+// tags below, with no file descriptor required. Keep the field names,
+// struct tags, and .proto definitions in sync by hand until a real codegen
+// step replaces this file.
+package fmservepb
+
+import "fmt"
+
+// GPUInfo mirrors fmsdk.FMFabricPartitionGpuInfo on the wire.
+type GPUInfo struct {
+	PhysicalId          uint32 `protobuf:"varint,1,opt,name=physical_id,json=physicalId" json:"physical_id,omitempty"`
+	Uuid                string `protobuf:"bytes,2,opt,name=uuid" json:"uuid,omitempty"`
+	PciBusId            string `protobuf:"bytes,3,opt,name=pci_bus_id,json=pciBusId" json:"pci_bus_id,omitempty"`
+	NumNvlinksAvailable uint32 `protobuf:"varint,4,opt,name=num_nvlinks_available,json=numNvlinksAvailable" json:"num_nvlinks_available,omitempty"`
+	MaxNumNvlinks       uint32 `protobuf:"varint,5,opt,name=max_num_nvlinks,json=maxNumNvlinks" json:"max_num_nvlinks,omitempty"`
+	NvlinkLineRateMbps  uint32 `protobuf:"varint,6,opt,name=nvlink_line_rate_mbps,json=nvlinkLineRateMbps" json:"nvlink_line_rate_mbps,omitempty"`
+}
+
+func (m *GPUInfo) Reset()         { *m = GPUInfo{} }
+func (m *GPUInfo) String() string { return fmt.Sprintf("%+v", *m) }
+func (*GPUInfo) ProtoMessage()    {}
+
+// PartitionInfo mirrors fmsdk.FMPartitionInfo on the wire.
+type PartitionInfo struct {
+	PartitionId uint32     `protobuf:"varint,1,opt,name=partition_id,json=partitionId" json:"partition_id,omitempty"`
+	IsActive    bool       `protobuf:"varint,2,opt,name=is_active,json=isActive" json:"is_active,omitempty"`
+	NumGpus     uint32     `protobuf:"varint,3,opt,name=num_gpus,json=numGpus" json:"num_gpus,omitempty"`
+	GpuInfo     []*GPUInfo `protobuf:"bytes,4,rep,name=gpu_info,json=gpuInfo" json:"gpu_info,omitempty"`
+}
+
+func (m *PartitionInfo) Reset()         { *m = PartitionInfo{} }
+func (m *PartitionInfo) String() string { return fmt.Sprintf("%+v", *m) }
+func (*PartitionInfo) ProtoMessage()    {}
+
+type ListPartitionsRequest struct{}
+
+func (m *ListPartitionsRequest) Reset()         { *m = ListPartitionsRequest{} }
+func (m *ListPartitionsRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ListPartitionsRequest) ProtoMessage()    {}
+
+type ListPartitionsResponse struct {
+	Partitions []*PartitionInfo `protobuf:"bytes,1,rep,name=partitions" json:"partitions,omitempty"`
+}
+
+func (m *ListPartitionsResponse) Reset()         { *m = ListPartitionsResponse{} }
+func (m *ListPartitionsResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ListPartitionsResponse) ProtoMessage()    {}
+
+type ActivatePartitionRequest struct {
+	PartitionId uint32 `protobuf:"varint,1,opt,name=partition_id,json=partitionId" json:"partition_id,omitempty"`
+}
+
+func (m *ActivatePartitionRequest) Reset()         { *m = ActivatePartitionRequest{} }
+func (m *ActivatePartitionRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ActivatePartitionRequest) ProtoMessage()    {}
+
+type ActivatePartitionResponse struct{}
+
+func (m *ActivatePartitionResponse) Reset()         { *m = ActivatePartitionResponse{} }
+func (m *ActivatePartitionResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ActivatePartitionResponse) ProtoMessage()    {}
+
+type DeactivatePartitionRequest struct {
+	PartitionId uint32 `protobuf:"varint,1,opt,name=partition_id,json=partitionId" json:"partition_id,omitempty"`
+}
+
+func (m *DeactivatePartitionRequest) Reset()         { *m = DeactivatePartitionRequest{} }
+func (m *DeactivatePartitionRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*DeactivatePartitionRequest) ProtoMessage()    {}
+
+type DeactivatePartitionResponse struct{}
+
+func (m *DeactivatePartitionResponse) Reset()         { *m = DeactivatePartitionResponse{} }
+func (m *DeactivatePartitionResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*DeactivatePartitionResponse) ProtoMessage()    {}
@@ -0,0 +1,99 @@
+// Hand-maintained counterpart to fmserve.pb.go (see its header): the service
+// interface and descriptor that a real protoc-gen-go-grpc run against
+// proto/fmserve.proto would produce.
+
+package fmservepb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// FabricManagerServer is the server API for the FabricManager service.
+type FabricManagerServer interface {
+	ListPartitions(context.Context, *ListPartitionsRequest) (*ListPartitionsResponse, error)
+	ActivatePartition(context.Context, *ActivatePartitionRequest) (*ActivatePartitionResponse, error)
+	DeactivatePartition(context.Context, *DeactivatePartitionRequest) (*DeactivatePartitionResponse, error)
+}
+
+// UnimplementedFabricManagerServer may be embedded to have forward compatible
+// implementations.
+type UnimplementedFabricManagerServer struct{}
+
+func (UnimplementedFabricManagerServer) ListPartitions(context.Context, *ListPartitionsRequest) (*ListPartitionsResponse, error) {
+	return nil, grpc.ErrServerStopped
+}
+
+func (UnimplementedFabricManagerServer) ActivatePartition(context.Context, *ActivatePartitionRequest) (*ActivatePartitionResponse, error) {
+	return nil, grpc.ErrServerStopped
+}
+
+func (UnimplementedFabricManagerServer) DeactivatePartition(context.Context, *DeactivatePartitionRequest) (*DeactivatePartitionResponse, error) {
+	return nil, grpc.ErrServerStopped
+}
+
+// RegisterFabricManagerServer registers srv with the gRPC server s.
+func RegisterFabricManagerServer(s grpc.ServiceRegistrar, srv FabricManagerServer) {
+	s.RegisterService(&fabricManagerServiceDesc, srv)
+}
+
+var fabricManagerServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fmserve.FabricManager",
+	HandlerType: (*FabricManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPartitions",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(ListPartitionsRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(FabricManagerServer).ListPartitions(ctx, in)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/fmserve.FabricManager/ListPartitions"}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(FabricManagerServer).ListPartitions(ctx, req.(*ListPartitionsRequest))
+				}
+				return interceptor(ctx, in, info, handler)
+			},
+		},
+		{
+			MethodName: "ActivatePartition",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(ActivatePartitionRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(FabricManagerServer).ActivatePartition(ctx, in)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/fmserve.FabricManager/ActivatePartition"}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(FabricManagerServer).ActivatePartition(ctx, req.(*ActivatePartitionRequest))
+				}
+				return interceptor(ctx, in, info, handler)
+			},
+		},
+		{
+			MethodName: "DeactivatePartition",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(DeactivatePartitionRequest)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(FabricManagerServer).DeactivatePartition(ctx, in)
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/fmserve.FabricManager/DeactivatePartition"}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(FabricManagerServer).DeactivatePartition(ctx, req.(*DeactivatePartitionRequest))
+				}
+				return interceptor(ctx, in, info, handler)
+			},
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/fmserve.proto",
+}
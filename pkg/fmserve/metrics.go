@@ -0,0 +1,75 @@
+package fmserve
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// metrics holds the Prometheus collectors published on /metrics. Gauges
+// describe the last-polled state of each partition; counters track every
+// activate/deactivate attempt and the FM return code it produced.
+type metrics struct {
+	partitionActive    *prometheus.GaugeVec
+	partitionNumGpus   *prometheus.GaugeVec
+	nvlinksAvailable   *prometheus.GaugeVec
+	nvlinksMax         *prometheus.GaugeVec
+	nvlinkLineRateMBps *prometheus.GaugeVec
+	activateAttempts   *prometheus.CounterVec
+	deactivateAttempts *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		partitionActive: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "fmctl",
+			Subsystem: "partition",
+			Name:      "active",
+			Help:      "Whether the fabric partition is currently active (1) or inactive (0).",
+		}, []string{"partition_id"}),
+		partitionNumGpus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "fmctl",
+			Subsystem: "partition",
+			Name:      "num_gpus",
+			Help:      "Number of GPUs belonging to the fabric partition.",
+		}, []string{"partition_id"}),
+		nvlinksAvailable: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "fmctl",
+			Subsystem: "partition",
+			Name:      "nvlinks_available",
+			Help:      "Total NVLinks currently available across the partition's GPUs.",
+		}, []string{"partition_id"}),
+		nvlinksMax: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "fmctl",
+			Subsystem: "partition",
+			Name:      "nvlinks_max",
+			Help:      "Total NVLinks possible across the partition's GPUs.",
+		}, []string{"partition_id"}),
+		nvlinkLineRateMBps: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "fmctl",
+			Subsystem: "partition",
+			Name:      "nvlink_line_rate_mbps",
+			Help:      "NVLink line rate in MB/s reported for the partition's GPUs.",
+		}, []string{"partition_id"}),
+		activateAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "fmctl",
+			Subsystem: "partition",
+			Name:      "activate_attempts_total",
+			Help:      "Activation attempts per partition, labeled by the FM return code received.",
+		}, []string{"partition_id", "fm_return_code"}),
+		deactivateAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "fmctl",
+			Subsystem: "partition",
+			Name:      "deactivate_attempts_total",
+			Help:      "Deactivation attempts per partition, labeled by the FM return code received.",
+		}, []string{"partition_id", "fm_return_code"}),
+	}
+
+	reg.MustRegister(
+		m.partitionActive,
+		m.partitionNumGpus,
+		m.nvlinksAvailable,
+		m.nvlinksMax,
+		m.nvlinkLineRateMBps,
+		m.activateAttempts,
+		m.deactivateAttempts,
+	)
+
+	return m
+}
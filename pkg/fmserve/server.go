@@ -0,0 +1,304 @@
+// Package fmserve implements `fmctl serve`, a long-running daemon that keeps
+// a single Fabric Manager connection open and exposes it over gRPC, a REST
+// gateway, and a Prometheus /metrics endpoint. This lets a fleet of nodes be
+// monitored and driven centrally instead of every operator holding the FM
+// Unix socket open directly.
+package fmserve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+	"github.com/lambdalabs/fmctl/pkg/fmserve/fmservepb"
+	"github.com/lambdalabs/fmctl/pkg/fmwatch"
+)
+
+// Config controls the FM connection and listener addresses for `fmctl serve`.
+type Config struct {
+	Address           string
+	TimeoutMs         uint32
+	AddressIsUnixSock bool
+
+	GRPCAddr    string
+	HTTPAddr    string
+	MetricsAddr string
+
+	// PollInterval is how often the daemon refreshes partition state from FM
+	// to publish gauges, analogous to the stats_period/fingerprint_period
+	// knobs in Nomad's Nvidia device plugin.
+	PollInterval time.Duration
+}
+
+// Server owns the FM connection and serves it over gRPC, REST, and metrics.
+type Server struct {
+	fmservepb.UnimplementedFabricManagerServer
+
+	cfg    Config
+	handle fmsdk.FMHandle
+	reg    *prometheus.Registry
+	mx     *metrics
+
+	// fmMu serializes every call into the FM SDK through handle. Nothing in
+	// pkg/fmsdk documents the underlying C handle as safe for concurrent
+	// use, and poll() runs on a ticker goroutine while ActivatePartition/
+	// DeactivatePartition run on gRPC/REST handler goroutines, so without
+	// this they can race on the same handle.
+	fmMu sync.Mutex
+
+	mu         sync.RWMutex
+	partitions []fmsdk.FMPartitionInfo
+	prevPoll   []fmsdk.FMPartitionInfo
+}
+
+// Run initializes Fabric Manager, starts the poll loop, and serves gRPC,
+// REST, and /metrics until ctx is canceled.
+func Run(ctx context.Context, cfg Config) error {
+	if ret := fmsdk.FMLibInit(); ret != fmsdk.FM_ST_SUCCESS {
+		log.Printf("serve: FMLibInit returned %v (continuing, FM may already be initialized)", ret)
+	}
+	defer fmsdk.FMLibShutdown()
+
+	handle, ret := fmsdk.FMConnect(fmsdk.FMConnectParams{
+		Version:             1,
+		AddressInfo:         cfg.Address,
+		TimeoutMs:           cfg.TimeoutMs,
+		AddressIsUnixSocket: cfg.AddressIsUnixSock,
+	})
+	if ret != fmsdk.FM_ST_SUCCESS {
+		return fmt.Errorf("serve: connect to Fabric Manager: %v", ret)
+	}
+	defer fmsdk.FMDisconnect(handle)
+
+	reg := prometheus.NewRegistry()
+	s := &Server{
+		cfg:    cfg,
+		handle: handle,
+		reg:    reg,
+		mx:     newMetrics(reg),
+	}
+	s.poll()
+
+	grpcSrv := grpc.NewServer(grpc.ForceServerCodec(fmservepb.Codec()))
+	fmservepb.RegisterFabricManagerServer(grpcSrv, s)
+
+	grpcLis, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		return fmt.Errorf("serve: listen gRPC on %s: %w", cfg.GRPCAddr, err)
+	}
+	go func() {
+		if err := grpcSrv.Serve(grpcLis); err != nil {
+			log.Printf("serve: gRPC server stopped: %v", err)
+		}
+	}()
+	defer grpcSrv.GracefulStop()
+
+	httpSrv := &http.Server{Addr: cfg.HTTPAddr, Handler: s.restMux()}
+	go func() {
+		if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("serve: REST server stopped: %v", err)
+		}
+	}()
+	defer httpSrv.Shutdown(context.Background())
+
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	metricsSrv := &http.Server{Addr: cfg.MetricsAddr, Handler: metricsMux}
+	go func() {
+		if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("serve: metrics server stopped: %v", err)
+		}
+	}()
+	defer metricsSrv.Shutdown(context.Background())
+
+	s.pollLoop(ctx)
+	return ctx.Err()
+}
+
+// pollLoop refreshes partition state and metrics every PollInterval until ctx
+// is canceled.
+func (s *Server) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.poll()
+		}
+	}
+}
+
+// poll refreshes the cached partition snapshot and updates gauges from it.
+func (s *Server) poll() {
+	s.fmMu.Lock()
+	partitions, ret := fmsdk.FMGetSupportedFabricPartitions(s.handle)
+	s.fmMu.Unlock()
+	if ret != fmsdk.FM_ST_SUCCESS {
+		log.Printf("serve: poll FMGetSupportedFabricPartitions: %v", ret)
+		return
+	}
+
+	s.mu.Lock()
+	prev := s.prevPoll
+	s.partitions = partitions
+	s.prevPoll = partitions
+	s.mu.Unlock()
+
+	// Reuse the same diffing engine fmctl watch is built on, so operators
+	// see fabric transitions in the daemon's logs without running a
+	// separate watch process alongside it.
+	for _, event := range fmwatch.Diff(prev, partitions) {
+		log.Printf("serve: %s partition %d", event.Type, event.PartitionID)
+	}
+
+	for _, p := range partitions {
+		id := strconv.FormatUint(uint64(p.PartitionID), 10)
+
+		active := float64(0)
+		if p.IsActive {
+			active = 1
+		}
+		s.mx.partitionActive.WithLabelValues(id).Set(active)
+		s.mx.partitionNumGpus.WithLabelValues(id).Set(float64(p.NumGpus))
+
+		var nvlinksAvail, nvlinksMax, lineRate uint32
+		for _, gpu := range p.GPUInfo {
+			nvlinksAvail += gpu.NumNvLinksAvailable
+			nvlinksMax += gpu.MaxNumNvLinks
+			lineRate = gpu.NvlinkLineRateMBps
+		}
+		s.mx.nvlinksAvailable.WithLabelValues(id).Set(float64(nvlinksAvail))
+		s.mx.nvlinksMax.WithLabelValues(id).Set(float64(nvlinksMax))
+		s.mx.nvlinkLineRateMBps.WithLabelValues(id).Set(float64(lineRate))
+	}
+}
+
+// ListPartitions implements fmservepb.FabricManagerServer.
+func (s *Server) ListPartitions(ctx context.Context, req *fmservepb.ListPartitionsRequest) (*fmservepb.ListPartitionsResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &fmservepb.ListPartitionsResponse{Partitions: make([]*fmservepb.PartitionInfo, len(s.partitions))}
+	for i, p := range s.partitions {
+		resp.Partitions[i] = toPBPartition(p)
+	}
+	return resp, nil
+}
+
+// ActivatePartition implements fmservepb.FabricManagerServer.
+func (s *Server) ActivatePartition(ctx context.Context, req *fmservepb.ActivatePartitionRequest) (*fmservepb.ActivatePartitionResponse, error) {
+	s.fmMu.Lock()
+	ret := fmsdk.FMActivateFabricPartition(s.handle, req.PartitionId)
+	s.fmMu.Unlock()
+	s.mx.activateAttempts.WithLabelValues(strconv.FormatUint(uint64(req.PartitionId), 10), ret.String()).Inc()
+	if ret != fmsdk.FM_ST_SUCCESS {
+		return nil, fmt.Errorf("activate partition %d: %v", req.PartitionId, ret)
+	}
+	s.poll()
+	return &fmservepb.ActivatePartitionResponse{}, nil
+}
+
+// DeactivatePartition implements fmservepb.FabricManagerServer.
+func (s *Server) DeactivatePartition(ctx context.Context, req *fmservepb.DeactivatePartitionRequest) (*fmservepb.DeactivatePartitionResponse, error) {
+	s.fmMu.Lock()
+	ret := fmsdk.FMDeactivateFabricPartition(s.handle, req.PartitionId)
+	s.fmMu.Unlock()
+	s.mx.deactivateAttempts.WithLabelValues(strconv.FormatUint(uint64(req.PartitionId), 10), ret.String()).Inc()
+	if ret != fmsdk.FM_ST_SUCCESS {
+		return nil, fmt.Errorf("deactivate partition %d: %v", req.PartitionId, ret)
+	}
+	s.poll()
+	return &fmservepb.DeactivatePartitionResponse{}, nil
+}
+
+// restMux serves a small REST gateway over the same gRPC service methods, so
+// operators without a gRPC client can still poll and drive partitions over
+// plain HTTP/JSON.
+func (s *Server) restMux() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/partitions", func(w http.ResponseWriter, r *http.Request) {
+		resp, err := s.ListPartitions(r.Context(), &fmservepb.ListPartitionsRequest{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, resp)
+	})
+
+	mux.HandleFunc("/v1/partitions/activate", func(w http.ResponseWriter, r *http.Request) {
+		id, err := partitionIDFromQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := s.ActivatePartition(r.Context(), &fmservepb.ActivatePartitionRequest{PartitionId: id}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/v1/partitions/deactivate", func(w http.ResponseWriter, r *http.Request) {
+		id, err := partitionIDFromQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := s.DeactivatePartition(r.Context(), &fmservepb.DeactivatePartitionRequest{PartitionId: id}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+func partitionIDFromQuery(r *http.Request) (uint32, error) {
+	raw := r.URL.Query().Get("partition_id")
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid partition_id %q: %w", raw, err)
+	}
+	return uint32(id), nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func toPBPartition(p fmsdk.FMPartitionInfo) *fmservepb.PartitionInfo {
+	gpuInfo := make([]*fmservepb.GPUInfo, len(p.GPUInfo))
+	for i, gpu := range p.GPUInfo {
+		gpuInfo[i] = &fmservepb.GPUInfo{
+			PhysicalId:          gpu.PhysicalID,
+			Uuid:                gpu.UUID,
+			PciBusId:            gpu.PCIBusID,
+			NumNvlinksAvailable: gpu.NumNvLinksAvailable,
+			MaxNumNvlinks:       gpu.MaxNumNvLinks,
+			NvlinkLineRateMbps:  gpu.NvlinkLineRateMBps,
+		}
+	}
+	return &fmservepb.PartitionInfo{
+		PartitionId: p.PartitionID,
+		IsActive:    p.IsActive,
+		NumGpus:     p.NumGpus,
+		GpuInfo:     gpuInfo,
+	}
+}
@@ -0,0 +1,80 @@
+package fmwatch
+
+import (
+	"time"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+// Diff compares two FMGetSupportedFabricPartitions snapshots and returns the
+// events that explain how prev became curr. prev may be nil for the first
+// snapshot, in which case every partition in curr is reported as appeared.
+func Diff(prev, curr []fmsdk.FMPartitionInfo) []Event {
+	now := time.Now()
+	prevByID := indexByID(prev)
+	currByID := indexByID(curr)
+
+	var events []Event
+
+	for id, c := range currByID {
+		p, existed := prevByID[id]
+		if !existed {
+			events = append(events, Event{
+				Type: EventPartitionAppeared, Time: now, PartitionID: id, NumGpus: c.NumGpus,
+			})
+			continue
+		}
+
+		if c.IsActive && !p.IsActive {
+			events = append(events, Event{Type: EventPartitionActivated, Time: now, PartitionID: id})
+		} else if !c.IsActive && p.IsActive {
+			events = append(events, Event{Type: EventPartitionDeactivated, Time: now, PartitionID: id})
+		}
+
+		avail, max, currDegraded := nvlinksDegraded(c)
+		_, _, prevDegraded := nvlinksDegraded(p)
+		switch {
+		case currDegraded && !prevDegraded:
+			events = append(events, Event{
+				Type: EventNvlinksDegraded, Time: now, PartitionID: id,
+				NvlinksAvailable: avail, NvlinksMax: max,
+			})
+		case !currDegraded && prevDegraded:
+			events = append(events, Event{
+				Type: EventNvlinksRecovered, Time: now, PartitionID: id,
+				NvlinksAvailable: avail, NvlinksMax: max,
+			})
+		}
+	}
+
+	for id, p := range prevByID {
+		if _, stillPresent := currByID[id]; !stillPresent {
+			events = append(events, Event{
+				Type: EventPartitionDisappeared, Time: now, PartitionID: id, NumGpus: p.NumGpus,
+			})
+		}
+	}
+
+	return events
+}
+
+// nvlinksDegraded reports whether any GPU in the partition has fewer NVLinks
+// available than its maximum, along with the partition-wide totals.
+func nvlinksDegraded(p fmsdk.FMPartitionInfo) (available, max uint32, degraded bool) {
+	for _, gpu := range p.GPUInfo {
+		available += gpu.NumNvLinksAvailable
+		max += gpu.MaxNumNvLinks
+		if gpu.NumNvLinksAvailable < gpu.MaxNumNvLinks {
+			degraded = true
+		}
+	}
+	return available, max, degraded
+}
+
+func indexByID(partitions []fmsdk.FMPartitionInfo) map[uint32]fmsdk.FMPartitionInfo {
+	m := make(map[uint32]fmsdk.FMPartitionInfo, len(partitions))
+	for _, p := range partitions {
+		m[p.PartitionID] = p
+	}
+	return m
+}
@@ -0,0 +1,86 @@
+package fmwatch
+
+import (
+	"testing"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+func partition(id uint32, active bool, nvlinksAvail, nvlinksMax uint32) fmsdk.FMPartitionInfo {
+	return fmsdk.FMPartitionInfo{
+		PartitionID: id,
+		IsActive:    active,
+		NumGpus:     1,
+		GPUInfo: []fmsdk.FMFabricPartitionGpuInfo{
+			{NumNvLinksAvailable: nvlinksAvail, MaxNumNvLinks: nvlinksMax},
+		},
+	}
+}
+
+func eventTypes(events []Event) []EventType {
+	types := make([]EventType, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func containsType(events []Event, t EventType) bool {
+	for _, e := range events {
+		if e.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDiffAppearedAndDisappeared(t *testing.T) {
+	curr := []fmsdk.FMPartitionInfo{partition(1, false, 18, 18)}
+	events := Diff(nil, curr)
+	if len(events) != 1 || events[0].Type != EventPartitionAppeared {
+		t.Fatalf("Diff(nil, curr) = %v, want [partition_appeared]", eventTypes(events))
+	}
+
+	events = Diff(curr, nil)
+	if len(events) != 1 || events[0].Type != EventPartitionDisappeared {
+		t.Fatalf("Diff(curr, nil) = %v, want [partition_disappeared]", eventTypes(events))
+	}
+}
+
+func TestDiffActivatedAndDeactivated(t *testing.T) {
+	inactive := []fmsdk.FMPartitionInfo{partition(1, false, 18, 18)}
+	active := []fmsdk.FMPartitionInfo{partition(1, true, 18, 18)}
+
+	events := Diff(inactive, active)
+	if len(events) != 1 || events[0].Type != EventPartitionActivated {
+		t.Fatalf("Diff(inactive, active) = %v, want [partition_activated]", eventTypes(events))
+	}
+
+	events = Diff(active, inactive)
+	if len(events) != 1 || events[0].Type != EventPartitionDeactivated {
+		t.Fatalf("Diff(active, inactive) = %v, want [partition_deactivated]", eventTypes(events))
+	}
+}
+
+// TestDiffNvlinksDegradedIsEdgeTriggered ensures nvlinks_degraded only fires
+// on the transition into a degraded state, not on every poll that the fabric
+// stays degraded, and that nvlinks_recovered fires on the way back out.
+func TestDiffNvlinksDegradedIsEdgeTriggered(t *testing.T) {
+	healthy := partition(1, true, 18, 18)
+	degraded := partition(1, true, 12, 18)
+
+	events := Diff([]fmsdk.FMPartitionInfo{healthy}, []fmsdk.FMPartitionInfo{degraded})
+	if !containsType(events, EventNvlinksDegraded) {
+		t.Fatalf("healthy->degraded = %v, want nvlinks_degraded", eventTypes(events))
+	}
+
+	events = Diff([]fmsdk.FMPartitionInfo{degraded}, []fmsdk.FMPartitionInfo{degraded})
+	if containsType(events, EventNvlinksDegraded) {
+		t.Fatalf("degraded->degraded = %v, want no nvlinks_degraded (repeated poll)", eventTypes(events))
+	}
+
+	events = Diff([]fmsdk.FMPartitionInfo{degraded}, []fmsdk.FMPartitionInfo{healthy})
+	if !containsType(events, EventNvlinksRecovered) {
+		t.Fatalf("degraded->healthy = %v, want nvlinks_recovered", eventTypes(events))
+	}
+}
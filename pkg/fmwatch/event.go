@@ -0,0 +1,36 @@
+// Package fmwatch diffs successive FMGetSupportedFabricPartitions snapshots
+// into structured events. fmctl watch polls FM and streams these events as
+// JSON lines, but the diffing engine is exported so fmctl serve and the DRA
+// plugin can reuse it to react to fabric changes (e.g. auto-deactivating a
+// partition whose NVLink count drops below a threshold) without every
+// consumer reimplementing the snapshot comparison.
+package fmwatch
+
+import "time"
+
+// EventType identifies the kind of partition state transition an Event
+// describes.
+type EventType string
+
+const (
+	EventPartitionActivated   EventType = "partition_activated"
+	EventPartitionDeactivated EventType = "partition_deactivated"
+	EventNvlinksDegraded      EventType = "nvlinks_degraded"
+	EventNvlinksRecovered     EventType = "nvlinks_recovered"
+	EventPartitionAppeared    EventType = "partition_appeared"
+	EventPartitionDisappeared EventType = "partition_disappeared"
+)
+
+// Event is a single observed transition between two snapshots.
+type Event struct {
+	Type        EventType `json:"type"`
+	Time        time.Time `json:"time"`
+	PartitionID uint32    `json:"partition_id"`
+
+	// NumGpus is set on partition_appeared/partition_disappeared.
+	NumGpus uint32 `json:"num_gpus,omitempty"`
+
+	// NvlinksAvailable/NvlinksMax are set on nvlinks_degraded/nvlinks_recovered.
+	NvlinksAvailable uint32 `json:"nvlinks_available,omitempty"`
+	NvlinksMax       uint32 `json:"nvlinks_max,omitempty"`
+}
@@ -0,0 +1,84 @@
+package fmwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/http"
+	"time"
+)
+
+// Sink receives every Event a Watcher emits.
+type Sink interface {
+	Emit(Event) error
+}
+
+// WriterSink writes each event as a JSON line to w, e.g. os.Stdout.
+type WriterSink struct {
+	w io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Emit(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = s.w.Write(data)
+	return err
+}
+
+// WebhookSink POSTs each event as JSON to a configured URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Emit(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("fmwatch: webhook POST %s: %w", s.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("fmwatch: webhook POST %s: status %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// SyslogSink writes each event to the local syslog daemon.
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+func NewSyslogSink() (*SyslogSink, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, "fmctl")
+	if err != nil {
+		return nil, fmt.Errorf("fmwatch: open syslog: %w", err)
+	}
+	return &SyslogSink{writer: w}, nil
+}
+
+func (s *SyslogSink) Emit(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.writer.Info(string(data))
+}
@@ -0,0 +1,56 @@
+package fmwatch
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lambdalabs/fmctl/pkg/fmsdk"
+)
+
+// Watcher polls FMGetSupportedFabricPartitions on handle at Interval, diffs
+// successive snapshots, and emits the resulting events to every Sink.
+type Watcher struct {
+	Handle   fmsdk.FMHandle
+	Interval time.Duration
+	Sinks    []Sink
+
+	prev []fmsdk.FMPartitionInfo
+}
+
+// Run polls and emits events until ctx is canceled.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	w.poll()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll fetches the current snapshot, diffs it against the last one seen, and
+// emits any resulting events.
+func (w *Watcher) poll() {
+	curr, ret := fmsdk.FMGetSupportedFabricPartitions(w.Handle)
+	if ret != fmsdk.FM_ST_SUCCESS {
+		log.Printf("fmwatch: FMGetSupportedFabricPartitions: %v", ret)
+		return
+	}
+
+	for _, event := range Diff(w.prev, curr) {
+		for _, sink := range w.Sinks {
+			if err := sink.Emit(event); err != nil {
+				log.Printf("fmwatch: sink emit failed: %v", err)
+			}
+		}
+	}
+
+	w.prev = curr
+}
@@ -0,0 +1,200 @@
+// Package nvmlx cross-correlates fmsdk's per-GPU fabric partition info with
+// NVML device identity. On DGX H100 / HGX H100+ systems,
+// FMFabricPartitionGpuInfo's UUID and PCIBusID come back empty and only
+// PhysicalID is populated, so operators otherwise have to cross-reference
+// `nvidia-smi -q` output by module ID by hand. nvmlx dlopen's
+// libnvidia-ml.so.1 at runtime (rather than linking it directly, since not
+// every host that runs fmctl has the NVML library installed) and resolves
+// each PhysicalID against nvmlDeviceGetModuleId, filling in UUID, PCI bus ID,
+// board serial, and MIG capability from the matching NVML device.
+package nvmlx
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+#include <string.h>
+
+typedef int nvmlReturn_t;
+typedef void* nvmlDevice_t;
+
+// Mirrors the layout of NVML's nvmlPciInfo_t; nvmlx reads only busId.
+typedef struct {
+	char busIdLegacy[16];
+	unsigned int domain;
+	unsigned int bus;
+	unsigned int device;
+	unsigned int pciDeviceId;
+	unsigned int pciSubSystemId;
+	char busId[32];
+} nvmlPciInfo_t;
+
+typedef nvmlReturn_t (*nvmlInit_v2_t)(void);
+typedef nvmlReturn_t (*nvmlShutdown_t)(void);
+typedef nvmlReturn_t (*nvmlDeviceGetCount_v2_t)(unsigned int*);
+typedef nvmlReturn_t (*nvmlDeviceGetHandleByIndex_v2_t)(unsigned int, nvmlDevice_t*);
+typedef nvmlReturn_t (*nvmlDeviceGetUUID_t)(nvmlDevice_t, char*, unsigned int);
+typedef nvmlReturn_t (*nvmlDeviceGetSerial_t)(nvmlDevice_t, char*, unsigned int);
+typedef nvmlReturn_t (*nvmlDeviceGetModuleId_t)(nvmlDevice_t, unsigned int*);
+typedef nvmlReturn_t (*nvmlDeviceGetMigMode_t)(nvmlDevice_t, unsigned int*, unsigned int*);
+typedef nvmlReturn_t (*nvmlDeviceGetPciInfo_v3_t)(nvmlDevice_t, nvmlPciInfo_t*);
+
+// fnTable is resolved via dlsym in nvmlx_dlopen on each Open and reused
+// until Close dlcloses the library; NVML itself is safe to call from
+// multiple goroutines once initialized.
+typedef struct {
+	void* handle;
+	nvmlInit_v2_t                   init;
+	nvmlShutdown_t                  shutdown;
+	nvmlDeviceGetCount_v2_t         getCount;
+	nvmlDeviceGetHandleByIndex_v2_t getHandleByIndex;
+	nvmlDeviceGetUUID_t             getUUID;
+	nvmlDeviceGetSerial_t           getSerial;
+	nvmlDeviceGetModuleId_t         getModuleId;
+	nvmlDeviceGetMigMode_t          getMigMode;
+	nvmlDeviceGetPciInfo_v3_t       getPciInfo;
+} nvmlFnTable;
+
+static void* nvmlx_dlsym(void* handle, const char* name) {
+	return dlsym(handle, name);
+}
+
+static int nvmlx_dlopen(nvmlFnTable* t) {
+	memset(t, 0, sizeof(*t));
+	t->handle = dlopen("libnvidia-ml.so.1", RTLD_NOW | RTLD_GLOBAL);
+	if (!t->handle) {
+		return -1;
+	}
+
+	t->init             = (nvmlInit_v2_t)nvmlx_dlsym(t->handle, "nvmlInit_v2");
+	t->shutdown         = (nvmlShutdown_t)nvmlx_dlsym(t->handle, "nvmlShutdown");
+	t->getCount         = (nvmlDeviceGetCount_v2_t)nvmlx_dlsym(t->handle, "nvmlDeviceGetCount_v2");
+	t->getHandleByIndex = (nvmlDeviceGetHandleByIndex_v2_t)nvmlx_dlsym(t->handle, "nvmlDeviceGetHandleByIndex_v2");
+	t->getUUID          = (nvmlDeviceGetUUID_t)nvmlx_dlsym(t->handle, "nvmlDeviceGetUUID");
+	t->getSerial        = (nvmlDeviceGetSerial_t)nvmlx_dlsym(t->handle, "nvmlDeviceGetSerial");
+	t->getModuleId      = (nvmlDeviceGetModuleId_t)nvmlx_dlsym(t->handle, "nvmlDeviceGetModuleId");
+	t->getMigMode       = (nvmlDeviceGetMigMode_t)nvmlx_dlsym(t->handle, "nvmlDeviceGetMigMode");
+	t->getPciInfo       = (nvmlDeviceGetPciInfo_v3_t)nvmlx_dlsym(t->handle, "nvmlDeviceGetPciInfo_v3");
+
+	if (!t->init || !t->shutdown || !t->getCount || !t->getHandleByIndex ||
+	    !t->getUUID || !t->getModuleId) {
+		dlclose(t->handle);
+		t->handle = NULL;
+		return -2;
+	}
+
+	return 0;
+}
+
+static void nvmlx_dlclose(nvmlFnTable* t) {
+	if (t->handle) {
+		dlclose(t->handle);
+		t->handle = NULL;
+	}
+}
+*/
+import "C"
+
+import "fmt"
+
+// GPUDetails is the NVML-resolved identity of a single GPU, keyed by the
+// PhysicalID reported alongside it in FMFabricPartitionGpuInfo.
+type GPUDetails struct {
+	PhysicalID  uint32
+	UUID        string
+	PCIBusID    string
+	BoardSerial string
+	MIGCapable  bool
+}
+
+// Enricher holds a dlopen'd libnvidia-ml.so.1 and the NVML session opened
+// against it. Callers must Close it when done.
+type Enricher struct {
+	fns C.nvmlFnTable
+}
+
+// Open dlopen's libnvidia-ml.so.1 and calls nvmlInit_v2. It returns an error
+// if the library isn't installed or initialization fails, so callers can
+// treat enrichment as a best-effort, optional step.
+func Open() (*Enricher, error) {
+	e := &Enricher{}
+	if rc := C.nvmlx_dlopen(&e.fns); rc != 0 {
+		return nil, fmt.Errorf("nvmlx: dlopen libnvidia-ml.so.1: rc=%d (NVML not installed?)", int(rc))
+	}
+	if rc := e.fns.init(); rc != 0 {
+		C.nvmlx_dlclose(&e.fns)
+		return nil, fmt.Errorf("nvmlx: nvmlInit_v2: rc=%d", int(rc))
+	}
+	return e, nil
+}
+
+// Close shuts down the NVML session and dlcloses the library.
+func (e *Enricher) Close() error {
+	rc := e.fns.shutdown()
+	C.nvmlx_dlclose(&e.fns)
+	if rc != 0 {
+		return fmt.Errorf("nvmlx: nvmlShutdown: rc=%d", int(rc))
+	}
+	return nil
+}
+
+// Resolve enumerates every NVML device and returns its GPUDetails keyed by
+// PhysicalID (NVML's module ID), for cross-correlation against
+// FMFabricPartitionGpuInfo entries whose UUID/PCIBusID came back empty.
+func (e *Enricher) Resolve() (map[uint32]GPUDetails, error) {
+	var count C.uint
+	if rc := e.fns.getCount(&count); rc != 0 {
+		return nil, fmt.Errorf("nvmlx: nvmlDeviceGetCount_v2: rc=%d", int(rc))
+	}
+
+	out := make(map[uint32]GPUDetails, int(count))
+
+	for i := C.uint(0); i < count; i++ {
+		var dev C.nvmlDevice_t
+		if rc := e.fns.getHandleByIndex(i, &dev); rc != 0 {
+			continue
+		}
+
+		var moduleID C.uint
+		if rc := e.fns.getModuleId(dev, &moduleID); rc != 0 {
+			continue
+		}
+
+		details := GPUDetails{PhysicalID: uint32(moduleID)}
+
+		var uuidBuf [96]C.char
+		if rc := e.fns.getUUID(dev, &uuidBuf[0], C.uint(len(uuidBuf))); rc == 0 {
+			details.UUID = C.GoString(&uuidBuf[0])
+		}
+
+		if e.fns.getSerial != nil {
+			var serialBuf [96]C.char
+			if rc := e.fns.getSerial(dev, &serialBuf[0], C.uint(len(serialBuf))); rc == 0 {
+				details.BoardSerial = C.GoString(&serialBuf[0])
+			}
+		}
+
+		if e.fns.getMigMode != nil {
+			// nvmlDeviceGetMigMode reports the *current* MIG enablement, not
+			// whether the GPU supports MIG at all: it returns success with
+			// current=pending=0 on a MIG-capable GPU that simply has MIG
+			// turned off. Capability is whether the call is supported at
+			// all (rc == 0), not whether mode is currently enabled.
+			var current, pending C.uint
+			if rc := e.fns.getMigMode(dev, &current, &pending); rc == 0 {
+				details.MIGCapable = true
+			}
+		}
+
+		if e.fns.getPciInfo != nil {
+			var pciInfo C.nvmlPciInfo_t
+			if rc := e.fns.getPciInfo(dev, &pciInfo); rc == 0 {
+				details.PCIBusID = C.GoString(&pciInfo.busId[0])
+			}
+		}
+
+		out[details.PhysicalID] = details
+	}
+
+	return out, nil
+}